@@ -0,0 +1,120 @@
+package lullaby
+
+import "errors"
+
+// ErrDependencyCycle is returned by Start when the registered dependency
+// graph contains a cycle and cannot be scheduled.
+var ErrDependencyCycle = errors.New("lullaby: dependency cycle detected")
+
+// ErrDependencyNotReady is returned by Start when a service that other
+// services depend on (via Depends or AddWithDeps) doesn't implement Ready.
+// Start can only gate a dependent's layer on its dependency actually being
+// up - rather than merely launched - by waiting on that dependency's Ready
+// channel; without one there is no such signal, and the ordering guarantee
+// Depends/AddWithDeps promise wouldn't hold.
+var ErrDependencyNotReady = errors.New("lullaby: a service with dependents must implement Ready")
+
+// intern assigns a stable integer ID to service, registering it with the
+// group if it hasn't been seen before. It must be called with lg.mu held.
+func (lg *Group) intern(service Service) int {
+	if id, ok := lg.serviceIDs[service]; ok {
+		return id
+	}
+	id := len(lg.idServices)
+	lg.serviceIDs[service] = id
+	lg.idServices = append(lg.idServices, service)
+	lg.states[service] = StateNew
+	return id
+}
+
+// Depends records that a depends on b, i.e. b must be started (and, once
+// started, considered up) before a starts, and a must be stopped before b
+// stops. Both services are registered with the group if they weren't
+// already. b must implement Ready, or Start will return
+// ErrDependencyNotReady - without it, there is no way for the group to know
+// when b is actually up rather than merely launched.
+func (lg *Group) Depends(a, b Service) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	aID := lg.intern(a)
+	bID := lg.intern(b)
+	lg.depIDs[aID] = append(lg.depIDs[aID], bID)
+}
+
+// AddWithDeps registers service with the group and records that it depends
+// on each of deps, equivalent to calling Add followed by Depends(service, d)
+// for every d in deps. Every dependency must implement Ready, or Start will
+// return ErrDependencyNotReady - see Depends.
+func (lg *Group) AddWithDeps(service Service, deps ...Service) {
+	lg.mu.Lock()
+	lg.intern(service)
+	lg.mu.Unlock()
+
+	for _, dep := range deps {
+		lg.Depends(service, dep)
+	}
+}
+
+// layers computes the services registered with the group as a sequence of
+// topologically ordered layers: every service in layer N depends on nothing
+// outside layers 0..N-1, and services within a layer have no dependency
+// relationship between them and so may start concurrently. It returns
+// ErrDependencyCycle if the dependency graph is not a DAG, or
+// ErrDependencyNotReady if a service with dependents doesn't implement
+// Ready.
+func (lg *Group) layers() ([][]Service, error) {
+	lg.mu.Lock()
+	idServices := append([]Service(nil), lg.idServices...)
+	depIDs := make(map[int][]int, len(lg.depIDs))
+	for id, deps := range lg.depIDs {
+		depIDs[id] = append([]int(nil), deps...)
+	}
+	lg.mu.Unlock()
+
+	indegree := make([]int, len(idServices))
+	dependents := make(map[int][]int)
+	for id, deps := range depIDs {
+		indegree[id] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+
+	const done = -1
+	remaining := len(idServices)
+	var result [][]Service
+	for remaining > 0 {
+		var layerIDs []int
+		for id := range idServices {
+			if indegree[id] == 0 {
+				layerIDs = append(layerIDs, id)
+			}
+		}
+		if len(layerIDs) == 0 {
+			return nil, ErrDependencyCycle
+		}
+
+		layer := make([]Service, len(layerIDs))
+		for i, id := range layerIDs {
+			layer[i] = idServices[id]
+			indegree[id] = done
+			remaining--
+			for _, dependent := range dependents[id] {
+				if indegree[dependent] > 0 {
+					indegree[dependent]--
+				}
+			}
+		}
+		result = append(result, layer)
+	}
+
+	for _, deps := range depIDs {
+		for _, dep := range deps {
+			if _, ok := idServices[dep].(Ready); !ok {
+				return nil, ErrDependencyNotReady
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package lullaby
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// orderedService records the order in which Start and Stop are invoked
+// across every instance sharing the same *[]string log, so tests can assert
+// on relative ordering between dependent services. It implements Ready so
+// the group gates dependent layers on it actually being up rather than on
+// it merely having been launched.
+type orderedService struct {
+	name string
+	log  *[]string
+	mu   *sync.Mutex
+	done chan struct{}
+}
+
+func newOrderedService(name string, log *[]string, mu *sync.Mutex) *orderedService {
+	return &orderedService{name: name, log: log, mu: mu, done: make(chan struct{})}
+}
+
+func (s *orderedService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	*s.log = append(*s.log, "start:"+s.name)
+	s.mu.Unlock()
+	close(s.done)
+	<-ctx.Done()
+	return nil
+}
+
+func (s *orderedService) Ready() <-chan struct{} {
+	return s.done
+}
+
+func (s *orderedService) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	*s.log = append(*s.log, "stop:"+s.name)
+	s.mu.Unlock()
+	return nil
+}
+
+func TestGroupDependencyOrder(t *testing.T) {
+	var mu sync.Mutex
+	var log []string
+
+	db := newOrderedService("db", &log, &mu)
+	api := newOrderedService("api", &log, &mu)
+
+	group := New(0)
+	group.AddWithDeps(api, db)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	<-db.done
+	<-api.done
+
+	group.Stop()
+	group.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"start:db", "start:api", "stop:api", "stop:db"}
+	if len(log) != len(want) {
+		t.Fatalf("got log %v, want %v", log, want)
+	}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Fatalf("got log %v, want %v", log, want)
+		}
+	}
+}
+
+func TestGroupDependencyNotReady(t *testing.T) {
+	group := New(0)
+	api := newMockService()
+	db := newMockService() // doesn't implement Ready
+
+	group.AddWithDeps(api, db)
+
+	err := group.Start()
+	if !errors.Is(err, ErrDependencyNotReady) {
+		t.Fatalf("got error %v, want ErrDependencyNotReady", err)
+	}
+}
+
+func TestGroupDependencyCycle(t *testing.T) {
+	group := New(0)
+	a := newMockService()
+	b := newMockService()
+
+	group.Depends(a, b)
+	group.Depends(b, a)
+
+	err := group.Start()
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("got error %v, want ErrDependencyCycle", err)
+	}
+}
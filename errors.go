@@ -0,0 +1,49 @@
+package lullaby
+
+import (
+	"fmt"
+)
+
+// ServiceError pairs an error with the service and lifecycle phase that
+// produced it.
+type ServiceError struct {
+	Service Service
+	Phase   Phase
+	Err     error
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("lullaby: %v %s: %v", e.Service, e.Phase, e.Err)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// GroupError aggregates every ServiceError produced while a Group was
+// running, so a single Wait call can report every Start/Stop failure
+// instead of only the first one. It implements Unwrap() []error so
+// errors.Is and errors.As work against any of the wrapped ServiceErrors.
+type GroupError struct {
+	Errors []*ServiceError
+}
+
+func (e *GroupError) Error() string {
+	return fmt.Sprintf("lullaby: %d service error(s) occurred", len(e.Errors))
+}
+
+func (e *GroupError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, serviceErr := range e.Errors {
+		errs[i] = serviceErr
+	}
+	return errs
+}
+
+// recordError appends a ServiceError to the group's aggregated error set,
+// surfaced to the caller via Wait.
+func (lg *Group) recordError(service Service, phase Phase, err error) {
+	lg.mu.Lock()
+	lg.errs = append(lg.errs, &ServiceError{Service: service, Phase: phase, Err: err})
+	lg.mu.Unlock()
+}
@@ -0,0 +1,71 @@
+package lullaby
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGroupWaitAggregatesStartFailure(t *testing.T) {
+	group := New(0)
+	service := newMockService()
+	service.shouldStartErr = true
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	err := group.Wait()
+	var groupErr *GroupError
+	if !errors.As(err, &groupErr) {
+		t.Fatalf("got error %v, want *GroupError", err)
+	}
+	if len(groupErr.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(groupErr.Errors))
+	}
+	if groupErr.Errors[0].Service != service || groupErr.Errors[0].Phase != PhaseStart {
+		t.Errorf("got %+v, want Service=service Phase=PhaseStart", groupErr.Errors[0])
+	}
+}
+
+func TestGroupWaitAggregatesStopFailure(t *testing.T) {
+	group := New(0)
+	service := newMockService()
+	service.shouldStopErr = true
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	service.waitForStart()
+
+	group.Stop()
+	err := group.Wait()
+
+	var groupErr *GroupError
+	if !errors.As(err, &groupErr) {
+		t.Fatalf("got error %v, want *GroupError", err)
+	}
+	if len(groupErr.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1", len(groupErr.Errors))
+	}
+	if groupErr.Errors[0].Service != service || groupErr.Errors[0].Phase != PhaseStop {
+		t.Errorf("got %+v, want Service=service Phase=PhaseStop", groupErr.Errors[0])
+	}
+}
+
+func TestGroupWaitNilOnSuccess(t *testing.T) {
+	group := New(0)
+	service := newMockService()
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	service.waitForStart()
+
+	group.Stop()
+	if err := group.Wait(); err != nil {
+		t.Fatalf("Wait returned error: %v, want nil", err)
+	}
+}
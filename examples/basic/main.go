@@ -63,6 +63,8 @@ func main() {
 	}
 
 	// Wait for shutdown
-	group.Wait()
+	if err := group.Wait(); err != nil {
+		log.Fatal(err)
+	}
 	log.Println("All servers stopped gracefully")
 }
@@ -0,0 +1,125 @@
+package lullaby
+
+// Phase identifies which lifecycle operation a hook or error relates to.
+type Phase int
+
+const (
+	// PhaseStart identifies a service's Start call.
+	PhaseStart Phase = iota
+	// PhaseStop identifies a service's Stop call.
+	PhaseStop
+	// PhaseReload identifies an OnReload callback invoked on SIGHUP.
+	PhaseReload
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseStart:
+		return "start"
+	case PhaseStop:
+		return "stop"
+	case PhaseReload:
+		return "reload"
+	default:
+		return "unknown"
+	}
+}
+
+// hooks holds the lifecycle callbacks registered on a Group. A zero-value
+// hooks is safe to use: every field defaults to a no-op.
+type hooks struct {
+	beforeStart []func(Service)
+	afterStart  []func(Service)
+	beforeStop  []func(Service)
+	afterStop   []func(Service, error)
+	onError     []func(Service, Phase, error)
+}
+
+// OnBeforeStart registers fn to run synchronously immediately before a
+// service's Start is called.
+func (lg *Group) OnBeforeStart(fn func(Service)) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.hooks.beforeStart = append(lg.hooks.beforeStart, fn)
+}
+
+// OnAfterStart registers fn to run synchronously once a service's Start
+// returns nil or, for a service implementing Ready, once its Ready channel
+// closes.
+func (lg *Group) OnAfterStart(fn func(Service)) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.hooks.afterStart = append(lg.hooks.afterStart, fn)
+}
+
+// OnBeforeStop registers fn to run synchronously immediately before a
+// service's Stop is called.
+func (lg *Group) OnBeforeStop(fn func(Service)) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.hooks.beforeStop = append(lg.hooks.beforeStop, fn)
+}
+
+// OnAfterStop registers fn to run synchronously once a service's Stop
+// returns, with the error it returned (nil on success).
+func (lg *Group) OnAfterStop(fn func(Service, error)) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.hooks.afterStop = append(lg.hooks.afterStop, fn)
+}
+
+// OnError registers fn to run synchronously whenever a service's Start or
+// Stop returns a non-nil error, in place of (not in addition to) AfterStart
+// or AfterStop for that call. The exception is a service implementing Ready
+// whose Start fails after its Ready channel has closed: AfterStart has
+// already fired for it by then, and OnError fires as well.
+func (lg *Group) OnError(fn func(Service, Phase, error)) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.hooks.onError = append(lg.hooks.onError, fn)
+}
+
+func (lg *Group) runBeforeStart(service Service) {
+	lg.mu.Lock()
+	fns := lg.hooks.beforeStart
+	lg.mu.Unlock()
+	for _, fn := range fns {
+		fn(service)
+	}
+}
+
+func (lg *Group) runAfterStart(service Service) {
+	lg.mu.Lock()
+	fns := lg.hooks.afterStart
+	lg.mu.Unlock()
+	for _, fn := range fns {
+		fn(service)
+	}
+}
+
+func (lg *Group) runBeforeStop(service Service) {
+	lg.mu.Lock()
+	fns := lg.hooks.beforeStop
+	lg.mu.Unlock()
+	for _, fn := range fns {
+		fn(service)
+	}
+}
+
+func (lg *Group) runAfterStop(service Service, err error) {
+	lg.mu.Lock()
+	fns := lg.hooks.afterStop
+	lg.mu.Unlock()
+	for _, fn := range fns {
+		fn(service, err)
+	}
+}
+
+func (lg *Group) runOnError(service Service, phase Phase, err error) {
+	lg.mu.Lock()
+	fns := lg.hooks.onError
+	lg.mu.Unlock()
+	for _, fn := range fns {
+		fn(service, phase, err)
+	}
+}
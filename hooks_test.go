@@ -0,0 +1,140 @@
+package lullaby
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGroupLifecycleHooks(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	}
+
+	group := New(0)
+	group.OnBeforeStart(func(Service) { record("before-start") })
+	group.OnAfterStart(func(Service) { record("after-start") })
+	group.OnBeforeStop(func(Service) { record("before-stop") })
+	group.OnAfterStop(func(Service, error) { record("after-stop") })
+
+	service := newMockService()
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	service.waitForStart()
+
+	// before-start must already have fired by the time Start has begun
+	// executing; after-start/before-stop/after-stop all fire once the
+	// service's context is cancelled and race against each other.
+	mu.Lock()
+	if len(events) != 1 || events[0] != "before-start" {
+		t.Fatalf("got events %v, want [before-start]", events)
+	}
+	mu.Unlock()
+
+	group.Stop()
+	group.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]int{"before-start": 1, "after-start": 1, "before-stop": 1, "after-stop": 1}
+	got := make(map[string]int)
+	for _, e := range events {
+		got[e]++
+	}
+	for event, count := range want {
+		if got[event] != count {
+			t.Errorf("got %d %q events, want %d (all events: %v)", got[event], event, count, events)
+		}
+	}
+}
+
+func TestGroupOnErrorOnStartFailure(t *testing.T) {
+	var mu sync.Mutex
+	var gotErr error
+	var gotPhase Phase
+	var afterStartCalled bool
+
+	group := New(0)
+	group.OnAfterStart(func(Service) {
+		mu.Lock()
+		afterStartCalled = true
+		mu.Unlock()
+	})
+	group.OnError(func(_ Service, phase Phase, err error) {
+		mu.Lock()
+		gotPhase = phase
+		gotErr = err
+		mu.Unlock()
+	})
+
+	service := newMockService()
+	service.shouldStartErr = true
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	group.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotErr == nil {
+		t.Fatal("OnError was not called")
+	}
+	if gotPhase != PhaseStart {
+		t.Errorf("got phase %v, want PhaseStart", gotPhase)
+	}
+	if afterStartCalled {
+		t.Error("AfterStart should not fire when Start fails")
+	}
+}
+
+func TestGroupOnErrorOnStopFailure(t *testing.T) {
+	var mu sync.Mutex
+	var gotErr error
+	var gotPhase Phase
+	var afterStopCalled bool
+
+	group := New(0)
+	group.OnAfterStop(func(_ Service, _ error) {
+		mu.Lock()
+		afterStopCalled = true
+		mu.Unlock()
+	})
+	group.OnError(func(_ Service, phase Phase, err error) {
+		mu.Lock()
+		gotPhase = phase
+		gotErr = err
+		mu.Unlock()
+	})
+
+	service := newMockService()
+	service.shouldStopErr = true
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	service.waitForStart()
+
+	group.Stop()
+	group.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotErr == nil {
+		t.Fatal("OnError was not called")
+	}
+	if gotPhase != PhaseStop {
+		t.Errorf("got phase %v, want PhaseStop", gotPhase)
+	}
+	if afterStopCalled {
+		t.Error("AfterStop should not fire when Stop fails")
+	}
+}
@@ -2,12 +2,12 @@ package lullaby
 
 import (
 	"context"
-	"github.com/sourcegraph/conc"
 	"os"
-	"os/signal"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/sourcegraph/conc"
 )
 
 type Startable interface {
@@ -25,61 +25,141 @@ type Service interface {
 
 // Group manages graceful stopping of multiple services
 type Group struct {
-	wg              *conc.WaitGroup
-	ctx             context.Context
-	cancel          context.CancelFunc
-	stopOnce        sync.Once
-	services        []Service
-	startedServices []Service  // Track successfully started services
-	mu              sync.Mutex // Protect startedServices
-	timeout         time.Duration
+	wg               *conc.WaitGroup
+	ctx              context.Context
+	cancel           context.CancelFunc
+	stopOnce         sync.Once
+	serviceIDs       map[Service]int // interned service IDs, assigned on first Add/Depends
+	idServices       []Service       // reverse lookup from interned ID to Service
+	depIDs           map[int][]int   // adjacency list: service ID -> IDs it depends on
+	states           map[Service]ServiceState
+	stateChanged     chan struct{}                // closed and replaced on every state transition, for WaitFor
+	startedLayers    [][]Service                  // Layers as scheduled by Start, for reverse-order Stop
+	hooks            hooks                        // Lifecycle callbacks registered via OnBeforeStart etc.
+	errs             []*ServiceError              // Start/Stop failures, aggregated and surfaced by Wait
+	supervised       map[Service]RestartPolicy    // services started via AddSupervised
+	supervisorStates map[Service]*supervisorState // restart bookkeeping, keyed by service
+	signals          []os.Signal                  // stop signals, defaults to SIGINT and SIGTERM; set via OnSignals
+	onReload         func(context.Context) error  // set via OnReload; nil disables SIGHUP handling
+	stopCancel       context.CancelFunc           // cancels the in-progress stop deadline, for forceShutdown
+	forceCh          chan struct{}                // closed by forceShutdown to unblock Wait with ErrForcedShutdown
+	forceOnce        sync.Once
+	stopDone         chan struct{} // closed once Stop (and stopAll) have fully finished, for handleSignals
+	mu               sync.Mutex    // Protect states, startedLayers, the dependency graph, hooks, errs and supervision state
+	timeout          time.Duration
 }
 
 // New creates a new Group with the specified timeout
 func New(timeout time.Duration) *Group {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Group{
-		wg:              conc.NewWaitGroup(),
-		ctx:             ctx,
-		cancel:          cancel,
-		timeout:         timeout,
-		services:        make([]Service, 0),
-		startedServices: make([]Service, 0),
+		wg:               conc.NewWaitGroup(),
+		ctx:              ctx,
+		cancel:           cancel,
+		timeout:          timeout,
+		serviceIDs:       make(map[Service]int),
+		idServices:       make([]Service, 0),
+		depIDs:           make(map[int][]int),
+		states:           make(map[Service]ServiceState),
+		stateChanged:     make(chan struct{}),
+		supervised:       make(map[Service]RestartPolicy),
+		supervisorStates: make(map[Service]*supervisorState),
+		signals:          []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		forceCh:          make(chan struct{}),
+		stopDone:         make(chan struct{}),
 	}
 }
 
 // Add registers a service with the group
 func (lg *Group) Add(service Service) {
-	lg.services = append(lg.services, service)
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.intern(service)
 }
 
+// Start brings up every registered service in dependency order: services are
+// scheduled in topological layers, and a layer is only launched once every
+// service in the previous layer has settled - for services implementing
+// Ready, that means the Ready channel has closed (see startService);
+// otherwise it's immediate, since there is no way to observe such a service
+// coming up short of Start returning. Within a layer, services start
+// concurrently. Start returns ErrDependencyCycle if Depends/AddWithDeps calls
+// have created a cycle. Layer gating happens in the background: Start itself
+// never blocks on a service settling, even a Ready one that takes a long
+// time to do so - it only computes the schedule before returning. Use Wait or
+// WaitReady to observe completion or readiness.
 func (lg *Group) Start() error {
+	layers, err := lg.layers()
+	if err != nil {
+		return err
+	}
+
+	lg.mu.Lock()
+	lg.startedLayers = layers
+	lg.mu.Unlock()
+
 	// Start signal handling
 	lg.wg.Go(func() {
 		lg.handleSignals()
 	})
 
-	// Start all services
-	for _, service := range lg.services {
-		srvc := service // Create new variable for closure
-		lg.wg.Go(func() {
-			// Track service as started before calling Start
-			lg.mu.Lock()
-			lg.startedServices = append(lg.startedServices, srvc)
-			lg.mu.Unlock()
-
-			if err := srvc.Start(lg.ctx); err != nil {
-				lg.Stop() // Trigger stop on failure
+	lg.wg.Go(func() {
+		for _, layer := range layers {
+			settled := make([]<-chan struct{}, 0, len(layer))
+			for _, service := range layer {
+				lg.mu.Lock()
+				policy, supervised := lg.supervised[service]
+				lg.mu.Unlock()
+
+				if supervised {
+					settled = append(settled, lg.startSupervised(service, policy))
+				} else {
+					settled = append(settled, lg.startService(service))
+				}
 			}
-		})
-	}
+			// Only advance to the next layer once every service in this one
+			// has settled, so a dependent never races its dependency coming
+			// up.
+			for _, ch := range settled {
+				<-ch
+			}
+		}
+	})
 
 	return nil
 }
 
-// Wait blocks until all services have completed
-func (lg *Group) Wait() {
-	lg.wg.Wait()
+// Wait blocks until all services have completed, then returns a *GroupError
+// aggregating every Start or Stop that failed, or nil if none did. If a
+// second stop signal forced an immediate shutdown (see OnSignals),
+// Wait returns as soon as that happens instead, with ErrForcedShutdown.
+func (lg *Group) Wait() error {
+	waitDone := make(chan struct{})
+	go func() {
+		lg.wg.Wait()
+		close(waitDone)
+	}()
+
+	select {
+	case <-waitDone:
+	case <-lg.forceCh:
+		return ErrForcedShutdown
+	}
+	// Both channels may have been ready at once; a forced shutdown wins.
+	select {
+	case <-lg.forceCh:
+		return ErrForcedShutdown
+	default:
+	}
+
+	lg.mu.Lock()
+	errs := lg.errs
+	lg.mu.Unlock()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &GroupError{Errors: errs}
 }
 
 // Stop initiates graceful stop of all services
@@ -87,42 +167,46 @@ func (lg *Group) Stop() {
 	lg.stopOnce.Do(func() {
 		lg.cancel()
 		lg.stopAll()
+		close(lg.stopDone)
 	})
 }
 
-// handleSignals sets up signal handling for graceful stop
-func (lg *Group) handleSignals() {
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	select {
-	case <-lg.ctx.Done():
-		return
-	case <-sigChan:
-		lg.Stop()
-	}
-}
-
-// stopAll gracefully stops all successfully started services
+// stopAll gracefully stops every service currently Starting or Running,
+// walking the dependency layers in reverse so a service stops before the
+// dependencies it relies on do (e.g. an HTTP server stops before the
+// database it depends on). Stopping a service that was never started, or
+// that is already Stopped or Failed, is a no-op.
 func (lg *Group) stopAll() {
 	stopCtx, cancel := context.WithTimeout(context.Background(), lg.timeout)
 	defer cancel()
 
-	// Create a WaitGroup for stop operations
-	stopWg := conc.NewWaitGroup()
-
-	// Get the list of services to stop under lock
 	lg.mu.Lock()
-	servicesToStop := lg.startedServices
+	lg.stopCancel = cancel
+	layers := lg.startedLayers
 	lg.mu.Unlock()
 
-	// Stop each service that was successfully started
-	for _, service := range servicesToStop {
-		srvc := service // Create new variable for closure
-		stopWg.Go(func() {
-			_ = srvc.Stop(stopCtx)
-		})
+	for i := len(layers) - 1; i >= 0; i-- {
+		stopWg := conc.NewWaitGroup()
+		for _, service := range layers[i] {
+			srvc := service // Create new variable for closure
+			state := lg.State(srvc)
+			if state != StateStarting && state != StateRunning {
+				continue
+			}
+			stopWg.Go(func() {
+				lg.setState(srvc, StateStopping)
+				lg.runBeforeStop(srvc)
+				err := srvc.Stop(stopCtx)
+				if err != nil {
+					lg.setState(srvc, StateFailed)
+					lg.recordError(srvc, PhaseStop, err)
+					lg.runOnError(srvc, PhaseStop, err)
+				} else {
+					lg.setState(srvc, StateStopped)
+					lg.runAfterStop(srvc, err)
+				}
+			})
+		}
+		stopWg.Wait()
 	}
-
-	stopWg.Wait()
 }
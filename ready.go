@@ -0,0 +1,144 @@
+package lullaby
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sourcegraph/conc"
+)
+
+// Ready is implemented by services that can signal they have finished
+// initialization while Start keeps running (e.g. a long-lived server whose
+// Start only returns at shutdown). The returned channel must be closed once,
+// when the service is ready to serve; it is never read from again after
+// that.
+type Ready interface {
+	Ready() <-chan struct{}
+}
+
+// ReadinessError is returned by WaitReady when one or more services did not
+// become ready within the deadline.
+type ReadinessError struct {
+	Services []Service
+}
+
+func (e *ReadinessError) Error() string {
+	return fmt.Sprintf("lullaby: %d service(s) did not become ready in time", len(e.Services))
+}
+
+// startService launches srvc's Start call and returns a channel that closes
+// once the group should consider the next dependency layer unblocked: for a
+// service implementing Ready, that's the Ready channel closing (or Start
+// returning first, e.g. on an early error); for a plain service it's
+// immediate, same as before readiness existed, since blocking a layer on a
+// long-running Start would deadlock the group. StateRunning follows the same
+// split: a Ready service reaches it when Ready closes (or Start returns nil
+// first); a plain one reaches it immediately, since nothing short of Start
+// returning would ever signal otherwise and that, for a long-running
+// service, only happens at shutdown. For a plain service AfterStart, unlike
+// the state transition, waits for Start to return successfully - firing it
+// at launch would fire it for a service that's about to fail; for a Ready
+// service it fires together with the transition to StateRunning.
+func (lg *Group) startService(srvc Service) <-chan struct{} {
+	lg.setState(srvc, StateStarting)
+
+	var readyCh <-chan struct{}
+	if r, ok := srvc.(Ready); ok {
+		readyCh = r.Ready()
+	}
+
+	// Run synchronously, before the goroutines below are even spawned, so
+	// BeforeStart always happens-before anything Start-related.
+	lg.runBeforeStart(srvc)
+
+	if readyCh == nil {
+		// Promoted here, synchronously and exactly once, so it can never
+		// race the Failed transition the goroutine below makes if Start
+		// errors out.
+		lg.setState(srvc, StateRunning)
+	}
+
+	settled := make(chan struct{})
+	if readyCh == nil {
+		close(settled)
+	}
+
+	markUp := func() {
+		lg.setState(srvc, StateRunning)
+		lg.runAfterStart(srvc)
+	}
+
+	// Everything past this point - settling the layer, marking the service
+	// up, and failing it - happens in this single tracked goroutine, so
+	// there's no second goroutine racing it to set StateFailed. That matters
+	// for a Ready service in particular: closing Ready and then erroring out
+	// of Start shortly after is ordinary, and this goroutine is still around
+	// to observe the error and fail the service after Ready already marked
+	// it running.
+	startDone := make(chan error, 1)
+	lg.wg.Go(func() {
+		go func() {
+			startDone <- srvc.Start(lg.ctx)
+		}()
+
+		var err error
+		if readyCh != nil {
+			select {
+			case <-readyCh:
+				markUp()
+				close(settled)
+				err = <-startDone
+			case err = <-startDone:
+				if err == nil {
+					markUp()
+				}
+				close(settled)
+			}
+		} else {
+			err = <-startDone
+			if err == nil {
+				lg.runAfterStart(srvc)
+			}
+		}
+
+		if err != nil {
+			lg.setState(srvc, StateFailed)
+			lg.recordError(srvc, PhaseStart, err)
+			lg.runOnError(srvc, PhaseStart, err)
+			lg.Stop() // Trigger stop on failure
+		}
+	})
+
+	return settled
+}
+
+// WaitReady blocks until every registered service has reached StateRunning,
+// or ctx is done, whichever comes first. On timeout or cancellation it
+// returns a *ReadinessError listing the services that hadn't become ready.
+func (lg *Group) WaitReady(ctx context.Context) error {
+	lg.mu.Lock()
+	services := append([]Service(nil), lg.idServices...)
+	lg.mu.Unlock()
+
+	var mu sync.Mutex
+	var notReady []Service
+
+	wg := conc.NewWaitGroup()
+	for _, service := range services {
+		srvc := service
+		wg.Go(func() {
+			if err := lg.WaitFor(srvc, StateRunning, ctx); err != nil {
+				mu.Lock()
+				notReady = append(notReady, srvc)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	if len(notReady) == 0 {
+		return nil
+	}
+	return &ReadinessError{Services: notReady}
+}
@@ -0,0 +1,162 @@
+package lullaby
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// readyMockService signals readiness independently of Start returning,
+// simulating a long-running server that only becomes usable partway through
+// its Start call.
+type readyMockService struct {
+	ready       chan struct{}
+	startCalled chan struct{}
+}
+
+func newReadyMockService() *readyMockService {
+	return &readyMockService{
+		ready:       make(chan struct{}),
+		startCalled: make(chan struct{}),
+	}
+}
+
+func (s *readyMockService) Start(ctx context.Context) error {
+	close(s.startCalled)
+	<-ctx.Done()
+	return nil
+}
+
+func (s *readyMockService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func (s *readyMockService) Stop(ctx context.Context) error {
+	return nil
+}
+
+func TestGroupReadinessGatesStateRunning(t *testing.T) {
+	group := New(0)
+	service := newReadyMockService()
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	<-service.startCalled
+
+	if got := group.State(service); got != StateStarting {
+		t.Fatalf("got state %v before Ready closes, want StateStarting", got)
+	}
+
+	close(service.ready)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := group.WaitFor(service, StateRunning, ctx); err != nil {
+		t.Fatalf("WaitFor(StateRunning) returned error: %v", err)
+	}
+
+	group.Stop()
+	group.Wait()
+}
+
+func TestGroupWaitReadySucceeds(t *testing.T) {
+	group := New(0)
+	service := newReadyMockService()
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	<-service.startCalled
+	close(service.ready)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := group.WaitReady(ctx); err != nil {
+		t.Fatalf("WaitReady returned error: %v", err)
+	}
+
+	group.Stop()
+	group.Wait()
+}
+
+// flakyReadyService closes its Ready channel - signaling it's up - and then
+// immediately fails Start, simulating a long-running service that becomes
+// ready and later crashes.
+type flakyReadyService struct {
+	ready chan struct{}
+}
+
+func newFlakyReadyService() *flakyReadyService {
+	return &flakyReadyService{ready: make(chan struct{})}
+}
+
+func (s *flakyReadyService) Start(ctx context.Context) error {
+	close(s.ready)
+	return errors.New("boom")
+}
+
+func (s *flakyReadyService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func (s *flakyReadyService) Stop(ctx context.Context) error {
+	return nil
+}
+
+// TestGroupReadyServiceFailsAfterReady checks that a service which closes
+// Ready and then fails Start still ends up StateFailed, not stuck in
+// StateRunning - the two outcomes must not race.
+func TestGroupReadyServiceFailsAfterReady(t *testing.T) {
+	group := New(time.Second)
+	service := newFlakyReadyService()
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := group.WaitFor(service, StateFailed, ctx); err != nil {
+		t.Fatalf("WaitFor(StateFailed) returned error: %v", err)
+	}
+
+	if got := group.State(service); got != StateFailed {
+		t.Fatalf("got state %v, want StateFailed", got)
+	}
+
+	group.Stop()
+	if err := group.Wait(); err == nil {
+		t.Fatal("Wait returned nil error, want the recorded Start failure")
+	}
+}
+
+func TestGroupWaitReadyTimesOut(t *testing.T) {
+	group := New(0)
+	service := newReadyMockService() // never becomes ready
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	<-service.startCalled
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := group.WaitReady(ctx)
+	var readinessErr *ReadinessError
+	if !errors.As(err, &readinessErr) {
+		t.Fatalf("got error %v, want *ReadinessError", err)
+	}
+	if len(readinessErr.Services) != 1 || readinessErr.Services[0] != service {
+		t.Fatalf("got services %v, want [service]", readinessErr.Services)
+	}
+
+	group.Stop()
+	group.Wait()
+}
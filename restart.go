@@ -0,0 +1,257 @@
+package lullaby
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RestartStrategy governs whether a supervised service is restarted after
+// its Start call returns.
+type RestartStrategy int
+
+const (
+	// RestartNever never restarts the service; Start returning, with or
+	// without an error, ends supervision.
+	RestartNever RestartStrategy = iota
+	// RestartOnFailure restarts the service only when Start returns a
+	// non-nil error.
+	RestartOnFailure
+	// RestartAlways restarts the service whenever Start returns, even if it
+	// exited cleanly.
+	RestartAlways
+)
+
+// Escalation governs what happens when a supervised service exceeds
+// MaxRestarts within Window.
+type Escalation int
+
+const (
+	// EscalateStopGroup stops the entire group, same as any other service
+	// failure.
+	EscalateStopGroup Escalation = iota
+	// EscalateStopSelf leaves the service in StateFailed and stops
+	// supervising it, without affecting the rest of the group.
+	EscalateStopSelf
+)
+
+// Backoff describes how long to wait between restart attempts.
+type Backoff struct {
+	Initial    time.Duration // delay before the first restart
+	Multiplier float64       // applied to the delay after every subsequent restart
+	Max        time.Duration // delay is capped here; zero means uncapped
+	Jitter     float64       // fraction of the delay to randomize by, e.g. 0.1 for +/-10%
+}
+
+// delay computes the backoff before the restart numbered attempt (0 for the
+// first restart, 1 for the second, and so on).
+func (b Backoff) delay(attempt int) time.Duration {
+	d := b.Initial
+	for i := 0; i < attempt; i++ {
+		d = time.Duration(float64(d) * b.Multiplier)
+		if b.Max > 0 && d > b.Max {
+			d = b.Max
+			break
+		}
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	if b.Jitter > 0 {
+		spread := float64(d) * b.Jitter
+		d += time.Duration((rand.Float64()*2 - 1) * spread)
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// RestartPolicy configures supervision for a service registered via
+// AddSupervised.
+type RestartPolicy struct {
+	Strategy    RestartStrategy
+	MaxRestarts int           // restarts allowed within Window before Escalation kicks in; zero means unlimited
+	Window      time.Duration // restart count resets once the service has stayed up longer than this
+	Backoff     Backoff
+	Escalation  Escalation
+}
+
+// supervisorState tracks restart bookkeeping for one supervised service.
+type supervisorState struct {
+	restarts int
+}
+
+// AddSupervised registers service with the group under the given restart
+// policy: instead of a single Start attempt, a supervisor goroutine restarts
+// the service according to policy whenever Start returns, applying backoff
+// between attempts and escalating if it restarts too often too quickly.
+func (lg *Group) AddSupervised(service Service, policy RestartPolicy) {
+	lg.mu.Lock()
+	lg.intern(service)
+	lg.supervised[service] = policy
+	lg.mu.Unlock()
+}
+
+// startSupervised launches the supervisor goroutine for service and returns
+// a channel that closes once its first Start attempt has settled (same
+// definition as startService), so dependency layers advance normally.
+// Subsequent restarts happen in the background without blocking layers.
+func (lg *Group) startSupervised(service Service, policy RestartPolicy) <-chan struct{} {
+	settled := make(chan struct{})
+	var settleOnce sync.Once
+	markSettled := func() { settleOnce.Do(func() { close(settled) }) }
+
+	lg.wg.Go(func() {
+		defer markSettled()
+
+		for attempt := 0; ; attempt++ {
+			if lg.ctx.Err() != nil {
+				return
+			}
+
+			attemptStart := time.Now()
+			startErr := lg.runSupervisedAttempt(service, markSettled)
+			upDuration := time.Since(attemptStart)
+
+			restarts := lg.recordSupervisedExit(service, policy, upDuration)
+			if !shouldRestart(policy.Strategy, startErr) {
+				return
+			}
+			if policy.MaxRestarts > 0 && restarts > policy.MaxRestarts {
+				lg.escalate(service, policy.Escalation)
+				return
+			}
+
+			select {
+			case <-time.After(policy.Backoff.delay(restarts - 1)):
+			case <-lg.ctx.Done():
+				return
+			}
+
+			lg.stopSupervised(service)
+		}
+	})
+
+	return settled
+}
+
+// runSupervisedAttempt runs one Start attempt for service, wiring up hooks,
+// readiness and state the same way startService does, and returns the error
+// Start returned (nil on success). onSettled is invoked (at most once,
+// across the whole supervision loop) as soon as this or a later attempt
+// settles.
+func (lg *Group) runSupervisedAttempt(service Service, onSettled func()) error {
+	lg.setState(service, StateStarting)
+	lg.runBeforeStart(service)
+
+	var readyCh <-chan struct{}
+	if r, ok := service.(Ready); ok {
+		readyCh = r.Ready()
+	}
+
+	if readyCh == nil {
+		// No Ready implementation: there's no signal short of Start
+		// returning, which for a long-running service only happens when it
+		// exits. Promote it to Running immediately, the same as
+		// startService does for an unsupervised plain service; AfterStart
+		// still only fires once Start actually returns successfully, so it
+		// isn't fired for an attempt that's about to fail.
+		lg.setState(service, StateRunning)
+	}
+
+	startDone := make(chan error, 1)
+	go func() {
+		startDone <- service.Start(lg.ctx)
+	}()
+
+	markUp := func() {
+		lg.setState(service, StateRunning)
+		lg.runAfterStart(service)
+	}
+
+	var err error
+	if readyCh != nil {
+		select {
+		case <-readyCh:
+			markUp()
+			onSettled()
+			err = <-startDone
+		case err = <-startDone:
+			if err == nil {
+				markUp()
+			}
+			onSettled()
+		}
+	} else {
+		onSettled()
+		err = <-startDone
+		if err == nil {
+			lg.runAfterStart(service)
+		}
+	}
+
+	if err != nil {
+		lg.setState(service, StateFailed)
+		lg.recordError(service, PhaseStart, err)
+		lg.runOnError(service, PhaseStart, err)
+	}
+	return err
+}
+
+// recordSupervisedExit updates and returns the restart count for service,
+// resetting it first if the service stayed up longer than policy.Window -
+// a service that ran that long is considered stable, not crash-looping.
+func (lg *Group) recordSupervisedExit(service Service, policy RestartPolicy, upDuration time.Duration) int {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+
+	state := lg.supervisorStates[service]
+	if state == nil {
+		state = &supervisorState{}
+		lg.supervisorStates[service] = state
+	}
+	if policy.Window > 0 && upDuration > policy.Window {
+		state.restarts = 0
+	}
+	state.restarts++
+	return state.restarts
+}
+
+// stopSupervised calls Stop on service to release its resources before a
+// restart, running the same hooks a normal shutdown would.
+func (lg *Group) stopSupervised(service Service) {
+	stopCtx, cancel := context.WithTimeout(context.Background(), lg.timeout)
+	defer cancel()
+
+	lg.setState(service, StateStopping)
+	lg.runBeforeStop(service)
+	err := service.Stop(stopCtx)
+	if err != nil {
+		lg.recordError(service, PhaseStop, err)
+		lg.runOnError(service, PhaseStop, err)
+	} else {
+		lg.runAfterStop(service, err)
+	}
+}
+
+func (lg *Group) escalate(service Service, escalation Escalation) {
+	switch escalation {
+	case EscalateStopSelf:
+		lg.setState(service, StateFailed)
+	default: // EscalateStopGroup
+		lg.Stop()
+	}
+}
+
+func shouldRestart(strategy RestartStrategy, err error) bool {
+	switch strategy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default: // RestartNever
+		return false
+	}
+}
@@ -0,0 +1,177 @@
+package lullaby
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyService fails its first failCount Start calls, then runs until its
+// context is cancelled.
+type flakyService struct {
+	failCount int32
+	attempts  int32
+	stopCalls int32
+	stopErr   bool
+}
+
+func (s *flakyService) Start(ctx context.Context) error {
+	n := atomic.AddInt32(&s.attempts, 1)
+	if n <= s.failCount {
+		return errors.New("not ready yet")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (s *flakyService) Stop(ctx context.Context) error {
+	atomic.AddInt32(&s.stopCalls, 1)
+	if s.stopErr {
+		return errors.New("stop failed")
+	}
+	return nil
+}
+
+func TestGroupSupervisedRestartsOnFailure(t *testing.T) {
+	group := New(time.Second)
+	service := &flakyService{failCount: 2}
+
+	group.AddSupervised(service, RestartPolicy{
+		Strategy: RestartOnFailure,
+		Backoff:  Backoff{Initial: time.Millisecond},
+	})
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	deadline := time.After(time.Second)
+	for atomic.LoadInt32(&service.attempts) < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for retries, got %d attempts", atomic.LoadInt32(&service.attempts))
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := group.WaitFor(service, StateRunning, ctx); err != nil {
+		t.Fatalf("WaitFor(StateRunning) returned error: %v", err)
+	}
+
+	group.Stop()
+	group.Wait()
+
+	if atomic.LoadInt32(&service.attempts) != 3 {
+		t.Errorf("got %d attempts, want 3", atomic.LoadInt32(&service.attempts))
+	}
+}
+
+func TestGroupSupervisedStopFailureDoesNotFireAfterStop(t *testing.T) {
+	group := New(time.Second)
+	service := &flakyService{failCount: 1, stopErr: true}
+
+	var mu sync.Mutex
+	var afterStopCalled bool
+	var gotPhase Phase
+	group.OnAfterStop(func(_ Service, _ error) {
+		mu.Lock()
+		afterStopCalled = true
+		mu.Unlock()
+	})
+	group.OnError(func(_ Service, phase Phase, _ error) {
+		mu.Lock()
+		gotPhase = phase
+		mu.Unlock()
+	})
+
+	group.AddSupervised(service, RestartPolicy{
+		Strategy: RestartOnFailure,
+		Backoff:  Backoff{Initial: time.Millisecond},
+	})
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	// Wait for the restart-triggered Stop (between attempt 0 and attempt 1)
+	// to have happened, so the OnError/AfterStop assertions below check
+	// that call rather than racing it.
+	deadline := time.After(time.Second)
+	for atomic.LoadInt32(&service.stopCalls) < 1 {
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for restart Stop, got %d stop calls", atomic.LoadInt32(&service.stopCalls))
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	group.Stop()
+	group.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotPhase != PhaseStop {
+		t.Errorf("OnError was not called for the failed restart Stop, got phase %v", gotPhase)
+	}
+	if afterStopCalled {
+		t.Error("AfterStop should not fire for a Stop that failed")
+	}
+}
+
+func TestGroupSupervisedEscalatesAfterMaxRestarts(t *testing.T) {
+	group := New(time.Second)
+	service := &flakyService{failCount: 1000} // always fails
+
+	var mu sync.Mutex
+	var escalated bool
+	group.OnError(func(_ Service, _ Phase, _ error) {})
+
+	group.AddSupervised(service, RestartPolicy{
+		Strategy:    RestartOnFailure,
+		MaxRestarts: 2,
+		Backoff:     Backoff{Initial: time.Millisecond},
+		Escalation:  EscalateStopSelf,
+	})
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	deadline := time.After(time.Second)
+	for {
+		if group.State(service) == StateFailed && atomic.LoadInt32(&service.attempts) >= 3 {
+			mu.Lock()
+			escalated = true
+			mu.Unlock()
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for escalation, state=%v attempts=%d", group.State(service), atomic.LoadInt32(&service.attempts))
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !escalated {
+		t.Fatal("expected supervisor to escalate after MaxRestarts")
+	}
+
+	// Give the supervisor loop a moment to settle, then confirm it stopped
+	// retrying rather than continuing to restart forever.
+	time.Sleep(20 * time.Millisecond)
+	attemptsAfterEscalation := atomic.LoadInt32(&service.attempts)
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&service.attempts) != attemptsAfterEscalation {
+		t.Error("supervisor kept restarting after escalation")
+	}
+
+	group.Stop()
+	group.Wait()
+}
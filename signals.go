@@ -0,0 +1,111 @@
+package lullaby
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ErrForcedShutdown is returned by Wait when a second stop signal is
+// received while a graceful Stop is already in progress, forcing Wait to
+// return immediately instead of waiting for services to finish.
+var ErrForcedShutdown = errors.New("lullaby: shutdown forced by repeated signal")
+
+// OnSignals replaces the group's default stop signals (SIGINT and SIGTERM)
+// with sigs. Receiving any of them triggers a graceful Stop; receiving a
+// second one while that Stop is still in progress forces an immediate
+// return from Wait instead of waiting on it to finish - see
+// ErrForcedShutdown.
+func (lg *Group) OnSignals(sigs ...os.Signal) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.signals = sigs
+}
+
+// OnReload registers fn to run whenever the group receives SIGHUP, instead
+// of SIGHUP stopping the group. fn is called with a context bounded by the
+// group's configured timeout; an error it returns is recorded the same way
+// a failed Start or Stop would be, under PhaseReload.
+func (lg *Group) OnReload(fn func(context.Context) error) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.onReload = fn
+}
+
+// handleSignals sets up signal handling for graceful stop, and optional
+// reload, until Stop has fully finished. It deliberately outlives the
+// group's context - ctx is canceled the instant Stop begins, but a second
+// stop signal must still be observable for as long as the graceful Stop
+// triggered by the first one is still running.
+func (lg *Group) handleSignals() {
+	lg.mu.Lock()
+	sigs := append([]os.Signal(nil), lg.signals...)
+	reloadEnabled := lg.onReload != nil
+	lg.mu.Unlock()
+
+	notify := sigs
+	if reloadEnabled {
+		notify = append(append([]os.Signal(nil), sigs...), syscall.SIGHUP)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, notify...)
+	defer signal.Stop(sigChan)
+
+	stopRequested := false
+	for {
+		select {
+		case <-lg.stopDone:
+			return
+		case sig := <-sigChan:
+			if reloadEnabled && sig == syscall.SIGHUP {
+				lg.handleReload()
+				continue
+			}
+			if stopRequested {
+				// A second stop signal while shutdown is already in
+				// progress: stop waiting on it and force an exit.
+				lg.forceShutdown()
+				return
+			}
+			stopRequested = true
+			lg.wg.Go(lg.Stop)
+		}
+	}
+}
+
+// handleReload invokes the registered OnReload callback, recording any
+// error it returns the same way a failed Start or Stop would be.
+func (lg *Group) handleReload() {
+	lg.mu.Lock()
+	fn := lg.onReload
+	lg.mu.Unlock()
+	if fn == nil {
+		return
+	}
+
+	reloadCtx, cancel := context.WithTimeout(lg.ctx, lg.timeout)
+	defer cancel()
+
+	if err := fn(reloadCtx); err != nil {
+		lg.recordError(nil, PhaseReload, err)
+		lg.runOnError(nil, PhaseReload, err)
+	}
+}
+
+// forceShutdown cancels the in-progress stop deadline, if any, and wakes
+// any goroutine blocked in Wait with ErrForcedShutdown.
+func (lg *Group) forceShutdown() {
+	lg.forceOnce.Do(func() {
+		lg.mu.Lock()
+		cancelStop := lg.stopCancel
+		lg.mu.Unlock()
+
+		if cancelStop != nil {
+			cancelStop()
+		}
+		close(lg.forceCh)
+	})
+}
@@ -0,0 +1,184 @@
+package lullaby
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// blockingStopService never returns from Stop on its own, so it only settles
+// once the stop deadline (or a forced shutdown) cancels its context.
+type blockingStopService struct{}
+
+func (s *blockingStopService) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (s *blockingStopService) Stop(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestGroupOnReloadInvoked(t *testing.T) {
+	group := New(time.Second)
+
+	reloaded := make(chan struct{}, 1)
+	group.OnReload(func(ctx context.Context) error {
+		reloaded <- struct{}{}
+		return nil
+	})
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	group.handleReload()
+
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("OnReload callback was not invoked")
+	}
+
+	group.Stop()
+	group.Wait()
+}
+
+func TestGroupOnReloadRecordsError(t *testing.T) {
+	group := New(time.Second)
+	wantErr := errors.New("reload failed")
+	group.OnReload(func(ctx context.Context) error { return wantErr })
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	group.handleReload()
+
+	group.Stop()
+	err := group.Wait()
+
+	var groupErr *GroupError
+	if !errors.As(err, &groupErr) {
+		t.Fatalf("got error %v, want *GroupError", err)
+	}
+	if len(groupErr.Errors) != 1 || groupErr.Errors[0].Phase != PhaseReload {
+		t.Fatalf("got errors %v, want one PhaseReload error", groupErr.Errors)
+	}
+	if !errors.Is(groupErr, wantErr) {
+		t.Errorf("errors.Is(groupErr, wantErr) = false, want true")
+	}
+}
+
+func TestGroupForceShutdownUnblocksWait(t *testing.T) {
+	group := New(time.Hour) // long enough that only a force would unblock Wait in time
+	service := &blockingStopService{}
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := group.WaitFor(service, StateRunning, ctx); err != nil {
+		t.Fatalf("WaitFor(StateRunning) returned error: %v", err)
+	}
+
+	go group.Stop()
+
+	// Give Stop a moment to start, then force it, as a second signal would.
+	time.Sleep(10 * time.Millisecond)
+	group.forceShutdown()
+
+	done := make(chan error, 1)
+	go func() { done <- group.Wait() }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrForcedShutdown) {
+			t.Fatalf("got error %v, want ErrForcedShutdown", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after forceShutdown")
+	}
+}
+
+// TestGroupRealSignalForcesShutdown delivers two real OS signals to the
+// running process - as a user pressing Ctrl-C twice would - and checks that
+// the second one forces Wait to return ErrForcedShutdown instead of hanging
+// until the stop deadline.
+func TestGroupRealSignalForcesShutdown(t *testing.T) {
+	group := New(time.Hour) // long enough that only a force would unblock Wait in time
+	group.OnSignals(syscall.SIGUSR1)
+	service := &blockingStopService{}
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := group.WaitFor(service, StateRunning, ctx); err != nil {
+		t.Fatalf("WaitFor(StateRunning) returned error: %v", err)
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("sending first signal: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond) // give handleSignals time to observe it and start Stop
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("sending second signal: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- group.Wait() }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrForcedShutdown) {
+			t.Fatalf("got error %v, want ErrForcedShutdown", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after a real second signal")
+	}
+}
+
+// TestGroupOnSignalsCustomSignal checks that a signal registered via
+// OnSignals actually triggers a graceful Stop when delivered for real,
+// rather than only when Stop is called directly.
+func TestGroupOnSignalsCustomSignal(t *testing.T) {
+	group := New(time.Second)
+	group.OnSignals(syscall.SIGUSR2)
+	service := newMockService()
+	group.Add(service)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	service.waitForStart()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("sending signal: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- group.Wait() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Wait returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after the registered signal was delivered")
+	}
+
+	if !service.wasStopCalled() {
+		t.Error("Stop was not called after the registered signal was delivered")
+	}
+}
@@ -0,0 +1,104 @@
+package lullaby
+
+import (
+	"context"
+	"fmt"
+)
+
+// ServiceState describes where a service is in its lifecycle.
+type ServiceState int
+
+const (
+	// StateNew is the state of a service that has been registered but not
+	// yet started.
+	StateNew ServiceState = iota
+	// StateStarting is the state of a service implementing Ready between
+	// Start being invoked and its Ready channel closing (or Start returning
+	// first). A service that does not implement Ready moves on to
+	// StateRunning as soon as it is launched; it does not stay in
+	// StateStarting until its Start returns.
+	StateStarting
+	// StateRunning is the state of a service that has started successfully.
+	StateRunning
+	// StateStopping is the state of a service between Stop being invoked
+	// and Stop returning.
+	StateStopping
+	// StateStopped is the state of a service whose Stop has returned.
+	StateStopped
+	// StateFailed is the state of a service whose Start or Stop returned an
+	// error.
+	StateFailed
+)
+
+func (s ServiceState) String() string {
+	switch s {
+	case StateNew:
+		return "new"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// setState records service's new state and wakes any goroutine blocked in
+// WaitFor.
+func (lg *Group) setState(service Service, state ServiceState) {
+	lg.mu.Lock()
+	lg.states[service] = state
+	close(lg.stateChanged)
+	lg.stateChanged = make(chan struct{})
+	lg.mu.Unlock()
+}
+
+// State returns service's current state. It returns StateNew for a service
+// that has never been registered with the group.
+func (lg *Group) State(service Service) ServiceState {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	return lg.states[service]
+}
+
+// Status returns a snapshot of every registered service's current state.
+func (lg *Group) Status() map[Service]ServiceState {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	status := make(map[Service]ServiceState, len(lg.states))
+	for service, state := range lg.states {
+		status[service] = state
+	}
+	return status
+}
+
+// WaitFor blocks until service reaches target state or ctx is cancelled, in
+// which case it returns ctx.Err(). It returns an error immediately if
+// service was never registered with the group.
+func (lg *Group) WaitFor(service Service, target ServiceState, ctx context.Context) error {
+	for {
+		lg.mu.Lock()
+		state, registered := lg.states[service]
+		changed := lg.stateChanged
+		lg.mu.Unlock()
+
+		if !registered {
+			return fmt.Errorf("lullaby: service not registered with group")
+		}
+		if state == target {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
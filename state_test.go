@@ -0,0 +1,85 @@
+package lullaby
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGroupStateTransitions(t *testing.T) {
+	group := New(0)
+	service := newMockService()
+	group.Add(service)
+
+	if got := group.State(service); got != StateNew {
+		t.Fatalf("got state %v before Start, want StateNew", got)
+	}
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	// mockService doesn't implement Ready, so it's considered Running as
+	// soon as it's launched - there's no signal short of Start returning,
+	// which for a long-running service like this one only happens at
+	// shutdown.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := group.WaitFor(service, StateRunning, ctx); err != nil {
+		t.Fatalf("WaitFor(StateRunning) returned error: %v", err)
+	}
+
+	group.Stop()
+	group.Wait()
+
+	if got := group.State(service); got != StateStopped {
+		t.Fatalf("got state %v after Stop, want StateStopped", got)
+	}
+}
+
+func TestGroupStatusSnapshot(t *testing.T) {
+	group := New(0)
+	a := newMockService()
+	b := newMockService()
+	group.Add(a)
+	group.Add(b)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	a.waitForStart()
+	b.waitForStart()
+
+	// Neither service implements Ready, so each is considered Running as
+	// soon as it's launched rather than only once Start returns (which, for
+	// a long-running service like mockService, only happens at shutdown).
+	status := group.Status()
+	if len(status) != 2 {
+		t.Fatalf("got %d entries, want 2", len(status))
+	}
+	if status[a] != StateRunning {
+		t.Errorf("got state %v for a, want StateRunning", status[a])
+	}
+	if status[b] != StateRunning {
+		t.Errorf("got state %v for b, want StateRunning", status[b])
+	}
+
+	group.Stop()
+	group.Wait()
+}
+
+func TestGroupWaitForContextCancelled(t *testing.T) {
+	group := New(0)
+	service := newMockService()
+	group.Add(service)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Service is never started, so it stays in StateNew and WaitFor for
+	// StateRunning must time out via ctx rather than block forever.
+	err := group.WaitFor(service, StateRunning, ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want context.DeadlineExceeded", err)
+	}
+}